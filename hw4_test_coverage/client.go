@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SearchRequest describes one query against SearchServer.
+type SearchRequest struct {
+	Limit      int
+	Offset     int
+	Query      string
+	OrderField string
+	OrderBy    int
+}
+
+// User is the shape SearchClient decodes search results into. It mirrors
+// the JSON SearchServerHandler writes, which is why Name (unlike MyUser on
+// the server side) is a plain field rather than something only produced by
+// a custom MarshalJSON.
+type User struct {
+	Id     int    `json:"id"`
+	Name   string `json:"name"`
+	Age    int    `json:"age"`
+	About  string `json:"about"`
+	Gender string `json:"gender"`
+}
+
+// SearchResponse is what SearchClient.FindUsers returns on success.
+type SearchResponse struct {
+	Users    []User
+	NextPage bool
+}
+
+// SearchClient is an HTTP client for SearchServerHandler.
+type SearchClient struct {
+	AccessToken string
+	URL         string
+}
+
+const maxLimit = 25
+
+// FindUsers is a thin wrapper around FindUsersContext for callers that don't
+// need cancellation or deadlines.
+func (sc *SearchClient) FindUsers(req SearchRequest) (*SearchResponse, error) {
+	return sc.FindUsersContext(context.Background(), req)
+}
+
+// FindUsersContext runs req against the server, aborting the outbound HTTP
+// request as soon as ctx is canceled or its deadline elapses.
+func (sc *SearchClient) FindUsersContext(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	if req.Limit < 0 {
+		return nil, &SearchError{Kind: ErrKindInvalidRequest, Field: "Limit", Message: "limit must be > 0"}
+	}
+	if req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	if req.Offset < 0 {
+		return nil, &SearchError{Kind: ErrKindInvalidRequest, Field: "Offset", Message: "offset must be > 0"}
+	}
+
+	// Ask the server for one extra row so we can tell whether another page
+	// follows without a second round trip.
+	limit := req.Limit + 1
+
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("offset", strconv.Itoa(req.Offset))
+	params.Set("order_field", req.OrderField)
+	params.Set("order_by", strconv.Itoa(req.OrderBy))
+	params.Set("query", req.Query)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, sc.URL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, &SearchError{Kind: ErrKindTransport, Message: fmt.Sprintf("unknown error %s", err)}
+	}
+	httpReq.Header.Set("AccessToken", sc.AccessToken)
+
+	httpClient := http.Client{Timeout: time.Second}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok && urlErr.Timeout() {
+			return nil, &SearchError{Kind: ErrKindTimeout, Message: fmt.Sprintf("timeout for %s", err)}
+		}
+		return nil, &SearchError{Kind: ErrKindTransport, Message: fmt.Sprintf("unknown error %s", err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &SearchError{Kind: ErrKindTransport, Message: fmt.Sprintf("unknown error %s", err)}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return nil, &SearchError{Kind: ErrKindBadAccessToken, Message: "Bad AccessToken"}
+	case http.StatusBadRequest:
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err != nil {
+			return nil, &SearchError{Kind: ErrKindDecode, Message: fmt.Sprintf("cant unpack error json: %s", err)}
+		}
+
+		code := errResp.Code
+		if code == "" {
+			code = errResp.Error // compat with the old ad-hoc body shape
+		}
+
+		if code == CodeBadOrderField {
+			return nil, &SearchError{
+				Kind:    ErrKindBadOrderField,
+				Field:   req.OrderField,
+				Message: fmt.Sprintf("OrderFeld %s invalid", req.OrderField),
+			}
+		}
+
+		field := errResp.Field
+		if field == "" {
+			field = errResp.OrderField // compat with the old ad-hoc body shape
+		}
+
+		return nil, &SearchError{
+			Kind:    ErrKindBadRequest,
+			Field:   field,
+			Message: fmt.Sprintf("unknown bad request error: %s", field),
+		}
+	case http.StatusInternalServerError:
+		return nil, &SearchError{Kind: ErrKindServerFatal, Message: "SearchServer fatal error"}
+	case http.StatusOK:
+		var wire struct {
+			Users []User `json:"Users"`
+		}
+		if err := json.Unmarshal(body, &wire); err != nil {
+			return nil, &SearchError{Kind: ErrKindDecode, Message: fmt.Sprintf("cant unpack result json: %s", err)}
+		}
+
+		users := wire.Users
+
+		nextPage := false
+		if len(users) == limit {
+			nextPage = true
+			users = users[:limit-1]
+		}
+
+		return &SearchResponse{Users: users, NextPage: nextPage}, nil
+	}
+
+	return nil, &SearchError{Kind: ErrKindTransport, Message: fmt.Sprintf("unknown error, status code %d", resp.StatusCode)}
+}
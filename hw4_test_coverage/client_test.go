@@ -1,214 +1,16 @@
 package main
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"sort"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
 )
 
-type XmlUsers struct {
-	XMLName xml.Name `xml:"root"`
-	Users   []MyUser `xml:"row"`
-}
-
-type MyUser struct {
-	Id         int    `xml:"id" json:"id"`
-	Name       string `xml:"-" json:"-"`
-	FirstName  string `xml:"first_name" json:"-"`
-	SecondName string `xml:"last_name" json:"-"`
-	Age        int    `xml:"age" json:"age"`
-	About      string `xml:"about" json:"about"`
-	Gender     string `xml:"gender" json:"gender"`
-}
-
-func (u *MyUser) getFullName() string {
-	return u.FirstName + " " + u.SecondName
-}
-
-func (u *MyUser) MarshalJSON() ([]byte, error) {
-	type Copy MyUser
-
-	return json.Marshal(&struct {
-		Name string `json:"name"`
-		*Copy
-	}{
-		Name: u.getFullName(),
-		Copy: (*Copy)(u),
-	})
-}
-
-// ------------
-// implement SearchServer
-// ------------
-const testToken string = "12345"
-
-type SearchServer struct {
-	pathToFile string
-}
-
-func (ss *SearchServer) getUsers(params SearchRequest) ([]MyUser, error) {
-	rawUsers, err := getUsersFromFile(ss.pathToFile)
-	if err != nil {
-		return nil, err
-	}
-
-	var resultUsers []MyUser
-
-	if params.Query != "" {
-		for _, user := range rawUsers {
-			nameContainsQuery := strings.Contains(user.getFullName(), params.Query)
-			aboutContainsQuery := strings.Contains(user.About, params.Query)
-
-			if nameContainsQuery || aboutContainsQuery {
-				resultUsers = append(resultUsers, user)
-			}
-		}
-	} else {
-		resultUsers = rawUsers
-	}
-
-	if params.OrderBy != 0 && params.OrderField != "" {
-		sortUsers(resultUsers, params.OrderField, params.OrderBy)
-	}
-
-	if params.Offset+params.Limit > len(resultUsers) {
-		return resultUsers[params.Offset:], nil
-	}
-
-	return resultUsers[params.Offset:params.Limit], nil
-}
-
-func getUsersFromFile(pathToFile string) ([]MyUser, error) {
-	file, err := os.Open(pathToFile)
-	if err != nil {
-		return nil, errors.New("Invalid resource path")
-	}
-
-	defer file.Close()
-
-	var usersList XmlUsers
-	if err := xml.NewDecoder(file).Decode(&usersList); err != nil {
-		return nil, errors.New("Error decoding file")
-	}
-
-	return usersList.Users, nil
-}
-
-func sortUsers(users []MyUser, orderField string, orderBy int) {
-	sort.Slice(users, func(i, j int) bool {
-		// a little bit of duplicating is better than complicating
-		// and using reflection for example
-		if orderField == "Id" {
-			if orderBy == -1 {
-				return users[i].Id > users[j].Id
-			} else {
-				return users[i].Id < users[j].Id
-			}
-		} else if orderField == "Age" {
-			if orderBy == -1 {
-				return users[i].Age > users[j].Age
-			} else {
-				return users[i].Age < users[j].Age
-			}
-		} else if orderField == "Name" {
-			if orderBy == -1 {
-				return users[i].getFullName() > users[j].getFullName()
-			} else {
-				return users[i].getFullName() < users[j].getFullName()
-			}
-		}
-
-		// fallback
-		return users[i].Id > users[j].Id
-	})
-}
-
-// ------------
-// HTTP Server handler
-// ------------
-func SearchServerHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	token := r.Header.Get("AccessToken")
-	if token == "" || token != testToken {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
-
-	searchRequest, err := getValidInput(r)
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		if err.Error() == "ErrorBadOrderField" {
-			io.WriteString(w, fmt.Sprintf(`{"StatusCode": 400, "Error": "ErrorBadOrderField"}`))
-		} else {
-			io.WriteString(w, fmt.Sprintf(`{"StatusCode": 400, "OrderField": "%s"}`, err.Error()))
-		}
-
-		return
-	}
-
-	searchServer := SearchServer{"./dataset.xml"}
-
-	users, err := searchServer.getUsers(searchRequest)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		io.WriteString(w, fmt.Sprintf(`{"StatusCode": 500, "error": "%s"}`, err.Error()))
-		return
-	}
-
-	usersJson, err := json.Marshal(users)
-
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		io.WriteString(w, fmt.Sprintf(`{"StatusCode": 500, "error": "Invalid data for json encoding"}`))
-		return
-	}
-
-	io.WriteString(w, string(usersJson))
-}
-
-func getValidInput(r *http.Request) (SearchRequest, error) {
-	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
-
-	if err != nil {
-		return SearchRequest{}, errors.New("limit")
-	}
-
-	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if err != nil {
-		return SearchRequest{}, errors.New("offset")
-	}
-
-	orderBy, err := strconv.Atoi(r.URL.Query().Get("order_by"))
-
-	if err != nil {
-		return SearchRequest{}, errors.New("order_by")
-
-	}
-
-	orderField := r.URL.Query().Get("order_field")
-	if orderField == "" {
-		return SearchRequest{}, errors.New("ErrorBadOrderField")
-	}
-
-	query := r.URL.Query().Get("query")
-
-	return SearchRequest{
-		limit, offset, query, orderField, orderBy,
-	}, nil
-}
-
 // ------------
 // tests
 // ------------
@@ -224,8 +26,8 @@ func TestRequestLimitLessThanZeroFails(t *testing.T) {
 		t.Error("Error is nil for Limit < 0")
 	}
 
-	if err.Error() != "limit must be > 0" {
-		t.Error("Invalid error text")
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Error("expected an ErrInvalidRequest SearchError")
 	}
 }
 
@@ -241,8 +43,8 @@ func TestRequestOffsetLessThanZeroFails(t *testing.T) {
 		t.Error("Error is nil for Offset < 0")
 	}
 
-	if err.Error() != "offset must be > 0" {
-		t.Error("Invalid error text")
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Error("expected an ErrInvalidRequest SearchError")
 	}
 }
 
@@ -259,8 +61,8 @@ func TestNoTokenFails(t *testing.T) {
 		t.Error("Error is nil for invalid token")
 	}
 
-	if err.Error() != "Bad AccessToken" {
-		t.Error("Invalid error text")
+	if !errors.Is(err, ErrBadAccessToken) {
+		t.Error("expected an ErrBadAccessToken SearchError")
 	}
 }
 
@@ -282,8 +84,8 @@ func TestLongServerResponseFails(t *testing.T) {
 		t.Error("Timeout reached but no error")
 	}
 
-	if !strings.Contains(err.Error(), "timeout") {
-		t.Error("Invalid error text")
+	if !errors.Is(err, ErrTimeout) {
+		t.Error("expected an ErrTimeout SearchError")
 	}
 }
 
@@ -297,8 +99,8 @@ func TestEmptyUrlFails(t *testing.T) {
 		t.Error("Nil url but no error")
 	}
 
-	if !strings.Contains(err.Error(), "unknown error") {
-		t.Error("Invalid error text")
+	if !errors.Is(err, ErrTransport) {
+		t.Error("expected an ErrTransport SearchError")
 	}
 }
 
@@ -319,8 +121,8 @@ func TestServer500Fails(t *testing.T) {
 		t.Error("Error must be not nil")
 	}
 
-	if err.Error() != "SearchServer fatal error" {
-		t.Error("Invalid error text")
+	if !errors.Is(err, ErrServerFatal) {
+		t.Error("expected an ErrServerFatal SearchError")
 	}
 }
 
@@ -344,8 +146,9 @@ func TestOrderFieldValidationErrorsFail(t *testing.T) {
 		t.Error("Error must be not nil")
 	}
 
-	if err.Error() != "OrderFeld test invalid" {
-		t.Error("Invalid error text")
+	var searchErr *SearchError
+	if !errors.As(err, &searchErr) || !errors.Is(err, ErrBadOrderField) || searchErr.Field != "test" {
+		t.Error("expected an ErrBadOrderField SearchError for field \"test\"")
 	}
 }
 
@@ -367,8 +170,8 @@ func TestOrderFieldValidationWrongJsonFail(t *testing.T) {
 		t.Error("Error must be not nil")
 	}
 
-	if !strings.Contains(err.Error(), "cant unpack error json") {
-		t.Error("Invalid error text")
+	if !errors.Is(err, ErrDecode) {
+		t.Error("expected an ErrDecode SearchError")
 	}
 }
 
@@ -392,8 +195,9 @@ func TestValidationErrorsFail(t *testing.T) {
 		t.Error("Error must be not nil")
 	}
 
-	if !strings.Contains(err.Error(), "unknown bad request error") {
-		t.Error("Invalid error text")
+	var searchErr *SearchError
+	if !errors.As(err, &searchErr) || !errors.Is(err, ErrBadRequest) || searchErr.Field != "Limit" {
+		t.Error("expected an ErrBadRequest SearchError for field \"Limit\"")
 	}
 }
 
@@ -495,7 +299,7 @@ func TestInvalidJsonErrorFail(t *testing.T) {
 		t.Error("Error must be not nil")
 	}
 
-	if !strings.Contains(err.Error(), "cant unpack result json") {
-		t.Error("Invalid error text")
+	if !errors.Is(err, ErrDecode) {
+		t.Error("expected an ErrDecode SearchError")
 	}
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFindUsersContextCanceledAborts(t *testing.T) {
+	searchService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer searchService.Close()
+
+	searchClient := &SearchClient{testToken, searchService.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := searchClient.FindUsersContext(ctx, SearchRequest{})
+	if err == nil {
+		t.Fatal("Error must be not nil when ctx is canceled")
+	}
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected an ErrTimeout SearchError, got: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// DatasetSource loads the user set SearchServer searches over. Load returns
+// unchanged=true when prevKey still identifies the current data, letting the
+// caller skip re-parsing; prevKey is whatever key a previous call to Load
+// returned, or "" on the first call.
+type DatasetSource interface {
+	Load(ctx context.Context, prevKey string) (users []MyUser, key string, unchanged bool, err error)
+}
+
+// datasetCache memoizes the last DatasetSource.Load result so a SearchServer
+// only re-reads and re-decodes its data when the source reports it changed.
+type datasetCache struct {
+	source DatasetSource
+
+	mu    sync.Mutex
+	key   string
+	users []MyUser
+	idx   Indexer
+}
+
+func newDatasetCache(source DatasetSource) *datasetCache {
+	return &datasetCache{source: source}
+}
+
+// reload refreshes c.users/c.key from source if it reports a change,
+// resetting the cached index so it's rebuilt against the new data. Caller
+// must hold c.mu.
+func (c *datasetCache) reload(ctx context.Context) error {
+	users, key, unchanged, err := c.source.Load(ctx, c.key)
+	if err != nil {
+		return err
+	}
+
+	if !unchanged {
+		c.users = users
+		c.key = key
+		c.idx = nil // the old index no longer matches c.users
+	}
+
+	return nil
+}
+
+// load returns the cached dataset, reloading it from source first if the
+// source reports it changed. The returned slice is a defensive copy, so
+// callers (in particular shardSortMerge's in-place sorts) are free to
+// reorder it without corrupting the cache or racing concurrent callers.
+func (c *datasetCache) load(ctx context.Context) ([]MyUser, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	out := make([]MyUser, len(c.users))
+	copy(out, c.users)
+	return out, nil
+}
+
+// index returns a defensive copy of the cached dataset together with an
+// Indexer built over that exact copy, both read under the same lock
+// acquisition so they always correspond: a result id from the returned
+// Indexer is always a valid position in the returned users slice, even if
+// another goroutine's load/index call reloads the dataset to a different
+// size in between (keying the index off rawUsers alone couldn't guarantee
+// that). The index itself is still built lazily, and only rebuilt when
+// reload actually picked up new data, so a server that never receives a
+// Query request never pays to build one.
+func (c *datasetCache) index(ctx context.Context) ([]MyUser, Indexer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.reload(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if c.idx == nil {
+		c.idx = buildIndex(c.users)
+	}
+
+	out := make([]MyUser, len(c.users))
+	copy(out, c.users)
+	return out, c.idx, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// httpSource is a DatasetSource that fetches dataset.xml-shaped data from a
+// remote URL, using the previous response's ETag/Last-Modified to ask for a
+// 304 instead of re-downloading and re-decoding an unchanged dataset.
+type httpSource struct {
+	url    string
+	client *http.Client
+
+	etag         string
+	lastModified string
+}
+
+func NewHTTPSource(url string, client *http.Client) DatasetSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpSource{url: url, client: client}
+}
+
+func (s *httpSource) Load(ctx context.Context, prevKey string) ([]MyUser, string, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, "", false, errors.New("Invalid resource path")
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	if s.lastModified != "" {
+		req.Header.Set("If-Modified-Since", s.lastModified)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", false, errors.New("Error decoding file")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, prevKey, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, errors.New("Invalid resource path")
+	}
+
+	users, err := decodeUsersXMLReader(resp.Body)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	s.lastModified = resp.Header.Get("Last-Modified")
+
+	key := s.etag
+	if key == "" {
+		key = s.lastModified
+	}
+
+	return users, key, false, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSourceFetchesAndRevalidates(t *testing.T) {
+	const xmlBody = `<root><row><id>1</id><first_name>Boyd</first_name><last_name>Wolf</last_name></row></root>`
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(xmlBody))
+	}))
+	defer server.Close()
+
+	cache := newDatasetCache(NewHTTPSource(server.URL, server.Client()))
+
+	users, err := cache.load(context.Background())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("got %d users, want 1", len(users))
+	}
+	if requests != 1 {
+		t.Fatalf("got %d requests, want 1", requests)
+	}
+
+	if _, err := cache.load(context.Background()); err != nil {
+		t.Fatalf("load (revalidate): %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("got %d requests after revalidate, want 2", requests)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+)
+
+// jsonlUser is one line of a newline-delimited-JSON dataset: the same
+// fields dataset.xml carries, just JSON-encoded one user per line instead
+// of XML rows, matching the line-oriented format hw3_bench's FastSearch
+// already parses datasets in.
+type jsonlUser struct {
+	Id        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Age       int    `json:"age"`
+	About     string `json:"about"`
+	Gender    string `json:"gender"`
+}
+
+// jsonlSource is a DatasetSource backed by a local newline-delimited JSON
+// file, keyed on the file's mtime like xmlFileSource.
+type jsonlSource struct {
+	pathToFile string
+}
+
+func NewJSONLFileSource(pathToFile string) DatasetSource {
+	return &jsonlSource{pathToFile: pathToFile}
+}
+
+func (s *jsonlSource) Load(ctx context.Context, prevKey string) ([]MyUser, string, bool, error) {
+	info, err := os.Stat(s.pathToFile)
+	if err != nil {
+		return nil, "", false, errors.New("Invalid resource path")
+	}
+
+	key := strconv.FormatInt(info.ModTime().UnixNano(), 10)
+	if key == prevKey {
+		return nil, key, true, nil
+	}
+
+	file, err := os.Open(s.pathToFile)
+	if err != nil {
+		return nil, "", false, errors.New("Invalid resource path")
+	}
+	defer file.Close()
+
+	users, err := decodeUsersJSONL(file)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return users, key, false, nil
+}
+
+func decodeUsersJSONL(r io.Reader) ([]MyUser, error) {
+	var users []MyUser
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var record jsonlUser
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, errors.New("Error decoding file")
+		}
+
+		users = append(users, MyUser{
+			Id:         record.Id,
+			FirstName:  record.FirstName,
+			SecondName: record.LastName,
+			Age:        record.Age,
+			About:      record.About,
+			Gender:     record.Gender,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New("Error decoding file")
+	}
+
+	return users, nil
+}
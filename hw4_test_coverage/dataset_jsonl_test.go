@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONLSourceDecodesUsers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dataset.jsonl")
+	writeDataset(t, path, `{"id":1,"first_name":"Boyd","last_name":"Wolf","age":22,"about":"bladder","gender":"male"}
+{"id":2,"first_name":"Hilda","last_name":"Mayer","age":30,"about":"dog","gender":"female"}
+`)
+
+	cache := newDatasetCache(NewJSONLFileSource(path))
+
+	users, err := cache.load(context.Background())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("got %d users, want 2", len(users))
+	}
+	if users[0].getFullName() != "Boyd Wolf" {
+		t.Errorf("got name %q, want %q", users[0].getFullName(), "Boyd Wolf")
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+)
+
+// xmlFileSource is a DatasetSource backed by a local dataset.xml file,
+// keyed on the file's mtime so unchanged files are never re-decoded.
+type xmlFileSource struct {
+	pathToFile string
+}
+
+func NewXMLFileSource(pathToFile string) DatasetSource {
+	return &xmlFileSource{pathToFile: pathToFile}
+}
+
+func (s *xmlFileSource) Load(ctx context.Context, prevKey string) ([]MyUser, string, bool, error) {
+	info, err := os.Stat(s.pathToFile)
+	if err != nil {
+		return nil, "", false, errors.New("Invalid resource path")
+	}
+
+	key := strconv.FormatInt(info.ModTime().UnixNano(), 10)
+	if key == prevKey {
+		return nil, key, true, nil
+	}
+
+	users, err := decodeUsersXML(s.pathToFile)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return users, key, false, nil
+}
+
+// decodeUsersXML streams the <row> elements out of an XML dataset file one
+// at a time via the xml.Decoder token stream, rather than decoding the
+// whole document into memory in a single Decode call.
+func decodeUsersXML(pathToFile string) ([]MyUser, error) {
+	file, err := os.Open(pathToFile)
+	if err != nil {
+		return nil, errors.New("Invalid resource path")
+	}
+	defer file.Close()
+
+	return decodeUsersXMLReader(file)
+}
+
+func decodeUsersXMLReader(r io.Reader) ([]MyUser, error) {
+	var users []MyUser
+
+	decoder := xml.NewDecoder(r)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("Error decoding file")
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var user MyUser
+		if err := decoder.DecodeElement(&user, &start); err != nil {
+			return nil, errors.New("Error decoding file")
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
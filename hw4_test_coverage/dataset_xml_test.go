@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDatasetCacheReloadsOnFileChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dataset.xml")
+	writeDataset(t, path, `<root><row><id>1</id><first_name>Boyd</first_name><last_name>Wolf</last_name></row></root>`)
+
+	ctx := context.Background()
+	cache := newDatasetCache(NewXMLFileSource(path))
+
+	users, err := cache.load(ctx)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("got %d users, want 1", len(users))
+	}
+
+	cached, err := cache.load(ctx)
+	if err != nil {
+		t.Fatalf("load (cached): %v", err)
+	}
+	if len(cached) != 1 {
+		t.Fatalf("cached load returned %d users, want 1", len(cached))
+	}
+
+	// Force a new mtime so the cache notices the file changed.
+	time.Sleep(10 * time.Millisecond)
+	writeDataset(t, path, `<root>
+		<row><id>1</id><first_name>Boyd</first_name><last_name>Wolf</last_name></row>
+		<row><id>2</id><first_name>Hilda</first_name><last_name>Mayer</last_name></row>
+	</root>`)
+
+	reloaded, err := cache.load(ctx)
+	if err != nil {
+		t.Fatalf("load (reloaded): %v", err)
+	}
+	if len(reloaded) != 2 {
+		t.Fatalf("got %d users after reload, want 2", len(reloaded))
+	}
+}
+
+func writeDataset(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+// Error codes SearchServerHandler reports in ErrorResponse.Code.
+const (
+	CodeBadOrderField = "ErrorBadOrderField"
+	CodeBadParam      = "ErrorBadParam"
+	CodeInternal      = "ErrorInternal"
+)
+
+// ErrorResponse is the JSON body SearchServerHandler writes for non-2xx
+// responses.
+//
+// Error and OrderField are compatibility shims for the ad-hoc bodies this
+// handler used to write by hand ({"Error":"..."} for a bad order field,
+// {"OrderField":"..."} for any other bad query param, {"error":"..."} for a
+// server fault). They're populated for one release so clients still
+// matching on those fields keep working. Error is always serialized under
+// the key "Error": a server fault's message is no longer written under the
+// old lowercase "error" key. SearchClient itself now reads
+// Code/Field/Message instead and they should be removed once callers have
+// migrated.
+type ErrorResponse struct {
+	StatusCode int    `json:"StatusCode"`
+	Code       string `json:"Code"`
+	Field      string `json:"Field,omitempty"`
+	Message    string `json:"Message,omitempty"`
+
+	Error      string `json:"Error,omitempty"`
+	OrderField string `json:"OrderField,omitempty"`
+}
+
+// ErrorKind classifies a SearchError so callers can branch on it with
+// errors.Is/errors.As instead of matching on Error() text.
+type ErrorKind int
+
+const (
+	ErrKindUnknown ErrorKind = iota
+	ErrKindInvalidRequest
+	ErrKindBadAccessToken
+	ErrKindBadOrderField
+	ErrKindBadRequest
+	ErrKindServerFatal
+	ErrKindTimeout
+	ErrKindDecode
+	ErrKindTransport
+)
+
+// SearchError is the error type SearchClient.FindUsers(Context) returns.
+type SearchError struct {
+	Kind    ErrorKind
+	Field   string
+	Message string
+}
+
+func (e *SearchError) Error() string { return e.Message }
+
+// Is lets errors.Is(err, ErrBadAccessToken) (etc.) match any SearchError of
+// the same Kind, regardless of Field/Message.
+func (e *SearchError) Is(target error) bool {
+	t, ok := target.(*SearchError)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind
+}
+
+// Sentinel SearchErrors for use with errors.Is. Only Kind is compared, so
+// these can be used directly as errors.Is targets.
+var (
+	ErrInvalidRequest = &SearchError{Kind: ErrKindInvalidRequest}
+	ErrBadAccessToken = &SearchError{Kind: ErrKindBadAccessToken}
+	ErrBadOrderField  = &SearchError{Kind: ErrKindBadOrderField}
+	ErrBadRequest     = &SearchError{Kind: ErrKindBadRequest}
+	ErrServerFatal    = &SearchError{Kind: ErrKindServerFatal}
+	ErrTimeout        = &SearchError{Kind: ErrKindTimeout}
+	ErrDecode         = &SearchError{Kind: ErrKindDecode}
+	ErrTransport      = &SearchError{Kind: ErrKindTransport}
+)
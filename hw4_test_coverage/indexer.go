@@ -0,0 +1,329 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Indexer answers search queries over the loaded user set, returning the
+// matching users ranked by relevance (best match first).
+type Indexer interface {
+	Search(query string) []indexMatch
+}
+
+// indexMatch is one hit produced by an Indexer: the index of the matching
+// user in the slice the index was built from, plus its term-frequency score
+// so callers can rank before applying OrderField.
+type indexMatch struct {
+	id    int
+	score int
+}
+
+// invertedIndex is an in-memory full-text index over MyUser.Name and
+// MyUser.About, built from the users a DatasetSource loaded (datasetCache
+// rebuilds it after the source reports new data).
+//
+// Supports a small query language: space-separated terms are ANDed by
+// default, "OR" between two terms turns that pair into an OR, a leading "-"
+// negates a term, and a "quoted phrase" matches consecutive tokens. A query
+// that uses none of this syntax is matched as a single phrase instead (see
+// parseQuery), so plain space-separated terms are only ANDed when some
+// other syntax appears in the same query. Terms
+// that aren't found as whole words fall back to a trigram scan so substring
+// queries (e.g. partial names) keep working.
+type invertedIndex struct {
+	postings map[string][]posting // token -> sorted postings
+	trigrams map[string][]int     // trigram -> user ids containing it, for substring fallback
+	docs     [][]string           // tokens per user, in original order, for phrase matching
+}
+
+type posting struct {
+	id int
+	tf int
+}
+
+func buildIndex(users []MyUser) *invertedIndex {
+	idx := &invertedIndex{
+		postings: make(map[string][]posting),
+		trigrams: make(map[string][]int),
+		docs:     make([][]string, len(users)),
+	}
+
+	counts := make(map[string]int)
+	for id, u := range users {
+		tokens := tokenize(u.getFullName() + " " + u.About)
+		idx.docs[id] = tokens
+
+		for k := range counts {
+			delete(counts, k)
+		}
+		for _, t := range tokens {
+			counts[t]++
+		}
+		for t, tf := range counts {
+			idx.postings[t] = append(idx.postings[t], posting{id: id, tf: tf})
+		}
+
+		seenTrigrams := make(map[string]bool)
+		for _, t := range tokens {
+			for _, tri := range trigrams(t) {
+				if !seenTrigrams[tri] {
+					seenTrigrams[tri] = true
+					idx.trigrams[tri] = append(idx.trigrams[tri], id)
+				}
+			}
+		}
+	}
+
+	for t := range idx.postings {
+		sort.Slice(idx.postings[t], func(i, j int) bool {
+			return idx.postings[t][i].id < idx.postings[t][j].id
+		})
+	}
+
+	return idx
+}
+
+// tokenize lowercases s and splits it on unicode word boundaries.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func trigrams(token string) []string {
+	if len(token) < 3 {
+		return []string{token}
+	}
+
+	runes := []rune(token)
+	grams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
+
+// clause is one parsed term of the query: a single token, or the tokens of
+// a quoted phrase to be matched consecutively.
+type clause struct {
+	tokens     []string
+	negate     bool
+	orWithNext bool
+}
+
+// parseQuery parses query into clauses. A bare query with none of the
+// boolean/negation/quoting syntax below is treated as a single phrase
+// clause, so a plain multi-word query like "foo bar" matches only users
+// whose tokens contain "foo" immediately followed by "bar", rather than
+// widening into foo AND bar. The phrase is matched on whole tokens,
+// case-insensitively, over the full name and About taken together; it is
+// not a raw substring match.
+func parseQuery(query string) []clause {
+	if !hasQuerySyntax(query) {
+		tokens := tokenize(query)
+		if len(tokens) == 0 {
+			return nil
+		}
+		return []clause{{tokens: tokens}}
+	}
+
+	var clauses []clause
+
+	fields := splitQueryFields(query)
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		if strings.EqualFold(field, "OR") {
+			if len(clauses) > 0 {
+				clauses[len(clauses)-1].orWithNext = true
+			}
+			continue
+		}
+		if strings.EqualFold(field, "AND") {
+			continue
+		}
+
+		negate := strings.HasPrefix(field, "-")
+		if negate {
+			field = strings.TrimPrefix(field, "-")
+		}
+
+		field = strings.Trim(field, `"`)
+		tokens := tokenize(field)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		clauses = append(clauses, clause{tokens: tokens, negate: negate})
+	}
+
+	return clauses
+}
+
+// hasQuerySyntax reports whether query uses any of the boolean query
+// language's special syntax: a quoted phrase, an explicit AND/OR, or a
+// leading "-" negation on some term.
+func hasQuerySyntax(query string) bool {
+	if strings.ContainsRune(query, '"') {
+		return true
+	}
+
+	for _, field := range strings.Fields(query) {
+		if strings.EqualFold(field, "OR") || strings.EqualFold(field, "AND") || strings.HasPrefix(field, "-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitQueryFields splits on whitespace but keeps "quoted phrases" intact.
+func splitQueryFields(query string) []string {
+	var fields []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuotes:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+
+	return fields
+}
+
+func (idx *invertedIndex) Search(query string) []indexMatch {
+	clauses := parseQuery(query)
+	if len(clauses) == 0 {
+		return nil
+	}
+
+	scores := make(map[int]int)
+
+	for i, c := range clauses {
+		ids := idx.matchClause(c)
+
+		switch {
+		case c.negate:
+			for id := range ids {
+				delete(scores, id)
+			}
+		case i == 0:
+			for id, tf := range ids {
+				scores[id] += tf
+			}
+		case clauses[i-1].orWithNext:
+			for id, tf := range ids {
+				scores[id] += tf
+			}
+		default:
+			// implicit AND: keep only ids present in both sets
+			for id := range scores {
+				if _, ok := ids[id]; !ok {
+					delete(scores, id)
+				}
+			}
+			for id, tf := range ids {
+				if _, ok := scores[id]; ok {
+					scores[id] += tf
+				}
+			}
+		}
+	}
+
+	matches := make([]indexMatch, 0, len(scores))
+	for id, score := range scores {
+		matches = append(matches, indexMatch{id: id, score: score})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].id < matches[j].id
+	})
+
+	return matches
+}
+
+// matchClause returns the user ids (and their term-frequency contribution)
+// that satisfy a single clause, trying an exact token/phrase match first and
+// falling back to a trigram substring scan.
+func (idx *invertedIndex) matchClause(c clause) map[int]int {
+	if len(c.tokens) > 1 {
+		return idx.matchPhrase(c.tokens)
+	}
+
+	token := c.tokens[0]
+	if postings, ok := idx.postings[token]; ok {
+		hits := make(map[int]int, len(postings))
+		for _, p := range postings {
+			hits[p.id] = p.tf
+		}
+		return hits
+	}
+
+	return idx.matchSubstring(token)
+}
+
+func (idx *invertedIndex) matchPhrase(tokens []string) map[int]int {
+	hits := make(map[int]int)
+
+	for id, doc := range idx.docs {
+		for i := 0; i+len(tokens) <= len(doc); i++ {
+			if docContainsPhraseAt(doc, tokens, i) {
+				hits[id]++
+			}
+		}
+	}
+
+	return hits
+}
+
+func docContainsPhraseAt(doc, tokens []string, at int) bool {
+	for j, t := range tokens {
+		if doc[at+j] != t {
+			return false
+		}
+	}
+	return true
+}
+
+func (idx *invertedIndex) matchSubstring(token string) map[int]int {
+	grams := trigrams(token)
+	candidates := make(map[int]int)
+
+	for _, g := range grams {
+		for _, id := range idx.trigrams[g] {
+			candidates[id]++
+		}
+	}
+
+	hits := make(map[int]int)
+	for id, count := range candidates {
+		if count < len(grams) {
+			continue
+		}
+		for _, t := range idx.docs[id] {
+			if strings.Contains(t, token) {
+				hits[id]++
+			}
+		}
+	}
+
+	return hits
+}
@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestIndexerBooleanAndNegation(t *testing.T) {
+	users := []MyUser{
+		{Id: 0, FirstName: "Boyd", SecondName: "Wolf", About: "Bladder cat"},
+		{Id: 1, FirstName: "Hilda", SecondName: "Mayer", About: "Unique dog"},
+		{Id: 2, FirstName: "Brooks", SecondName: "Aguilar", About: "Bladder dog"},
+	}
+
+	idx := buildIndex(users)
+
+	if ids := idsOf(idx.Search("bladder AND dog")); !sameSet(ids, []int{2}) {
+		t.Errorf("AND query matched %v, want [2]", ids)
+	}
+
+	if ids := idsOf(idx.Search("wolf OR aguilar")); !sameSet(ids, []int{0, 2}) {
+		t.Errorf("OR query matched %v, want [0 2]", ids)
+	}
+
+	if ids := idsOf(idx.Search("dog -unique")); !sameSet(ids, []int{2}) {
+		t.Errorf("negated query matched %v, want [2]", ids)
+	}
+}
+
+func TestIndexerPhraseAndSubstring(t *testing.T) {
+	users := []MyUser{
+		{Id: 0, FirstName: "Boyd", SecondName: "Wolf", About: "enjoys bladder surgery"},
+		{Id: 1, FirstName: "Hilda", SecondName: "Mayer", About: "bladder enjoys surgery"},
+	}
+
+	idx := buildIndex(users)
+
+	if ids := idsOf(idx.Search(`"enjoys bladder"`)); !sameSet(ids, []int{0}) {
+		t.Errorf("phrase query matched %v, want [0]", ids)
+	}
+
+	if ids := idsOf(idx.Search("bladd")); !sameSet(ids, []int{0, 1}) {
+		t.Errorf("substring fallback matched %v, want [0 1]", ids)
+	}
+}
+
+func idsOf(matches []indexMatch) []int {
+	ids := make([]int, len(matches))
+	for i, m := range matches {
+		ids[i] = m.id
+	}
+	return ids
+}
+
+func sameSet(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[int]bool, len(want))
+	for _, id := range want {
+		seen[id] = true
+	}
+	for _, id := range got {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// ------------
+// implement SearchServer
+// ------------
+const testToken string = "12345"
+
+type MyUser struct {
+	Id         int    `xml:"id" json:"id"`
+	Name       string `xml:"-" json:"-"`
+	FirstName  string `xml:"first_name" json:"-"`
+	SecondName string `xml:"last_name" json:"-"`
+	Age        int    `xml:"age" json:"age"`
+	About      string `xml:"about" json:"about"`
+	Gender     string `xml:"gender" json:"gender"`
+}
+
+func (u *MyUser) getFullName() string {
+	return u.FirstName + " " + u.SecondName
+}
+
+func (u *MyUser) MarshalJSON() ([]byte, error) {
+	type Copy MyUser
+
+	return json.Marshal(&struct {
+		Name string `json:"name"`
+		*Copy
+	}{
+		Name: u.getFullName(),
+		Copy: (*Copy)(u),
+	})
+}
+
+// usersResponse is the JSON body SearchServerHandler writes on success.
+type usersResponse struct {
+	Users []MyUser `json:"Users"`
+}
+
+type SearchServer struct {
+	cache *datasetCache
+}
+
+// NewSearchServer builds a SearchServer that reads its users from source,
+// so the same handler can serve dataset.xml, a JSONL stream or a remote
+// HTTP dataset without any code change here.
+func NewSearchServer(source DatasetSource) *SearchServer {
+	return &SearchServer{
+		cache: newDatasetCache(source),
+	}
+}
+
+func (ss *SearchServer) getUsers(ctx context.Context, params SearchRequest) ([]MyUser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var resultUsers []MyUser
+
+	if params.Query != "" {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// rawUsers and idx come from the same cache.index call so a match id
+		// is always a valid position in rawUsers, even if a concurrent
+		// request reloads the dataset to a different size in between.
+		rawUsers, idx, err := ss.cache.index(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := idx.Search(params.Query)
+
+		resultUsers = make([]MyUser, len(matches))
+		for i, m := range matches {
+			resultUsers[i] = rawUsers[m.id]
+		}
+	} else {
+		users, err := ss.cache.load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resultUsers = users
+	}
+
+	total := len(resultUsers)
+	start := params.Offset
+	if start > total {
+		start = total
+	}
+	end := clampEnd(start, params.Limit, total)
+
+	if params.OrderBy != 0 && params.OrderField != "" {
+		// The query itself was already narrowed down by the Indexer above,
+		// so sorting is the remaining single-threaded cost here. When `end`
+		// is a small share of the matches, shardSortMerge shards them across
+		// GOMAXPROCS workers and k-way merges them back, stopping as soon as
+		// we have the `end` results this page needs; otherwise it sorts them
+		// in a single sortUsers call.
+		top, err := shardSortMerge(ctx, resultUsers, params.OrderField, params.OrderBy, end)
+		if err != nil {
+			return nil, err
+		}
+		resultUsers = top
+	}
+
+	return copyPage(ctx, resultUsers, start, end)
+}
+
+func clampEnd(start, limit, total int) int {
+	if limit <= 0 {
+		return total
+	}
+
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return end
+}
+
+// copyPage returns a copy of users[start:end], checking ctx first so a
+// request canceled just before this last step doesn't pay to copy a page
+// nobody will read. resultUsers is already fully materialized and sorted by
+// the time this runs, so there's nothing left here to stream lazily.
+func copyPage(ctx context.Context, users []MyUser, start, end int) ([]MyUser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]MyUser, end-start)
+	copy(out, users[start:end])
+	return out, nil
+}
+
+// userLess reports whether a should sort before b for the given
+// OrderField/OrderBy, matching the semantics SearchRequest has always used:
+// OrderBy == -1 reverses the field's natural order, and an unrecognized
+// OrderField falls back to descending Id. Id is always the final tie-break,
+// so the result is a strict total order: shardSortMerge's per-shard sorts
+// and k-way merge agree with a plain sortUsers on the same data, even when
+// many rows share the same OrderField value.
+func userLess(a, b MyUser, orderField string, orderBy int) bool {
+	// a little bit of duplicating is better than complicating
+	// and using reflection for example
+	if orderField == "Id" {
+		if orderBy == -1 {
+			return a.Id > b.Id
+		}
+		return a.Id < b.Id
+	} else if orderField == "Age" {
+		if a.Age != b.Age {
+			if orderBy == -1 {
+				return a.Age > b.Age
+			}
+			return a.Age < b.Age
+		}
+		return a.Id < b.Id
+	} else if orderField == "Name" {
+		if a.getFullName() != b.getFullName() {
+			if orderBy == -1 {
+				return a.getFullName() > b.getFullName()
+			}
+			return a.getFullName() < b.getFullName()
+		}
+		return a.Id < b.Id
+	}
+
+	// fallback
+	return a.Id > b.Id
+}
+
+// sortUsers sorts in place, checking ctx every cancelCheckEvery comparisons
+// so a canceled request stops doing useless work instead of always running
+// the sort to completion.
+const cancelCheckEvery = 256
+
+func sortUsers(ctx context.Context, users []MyUser, orderField string, orderBy int) error {
+	var cancelled error
+	comparisons := 0
+
+	sort.Slice(users, func(i, j int) bool {
+		if cancelled != nil {
+			return false
+		}
+
+		comparisons++
+		if comparisons%cancelCheckEvery == 0 {
+			select {
+			case <-ctx.Done():
+				cancelled = ctx.Err()
+				return false
+			default:
+			}
+		}
+
+		return userLess(users[i], users[j], orderField, orderBy)
+	})
+
+	return cancelled
+}
+
+// ------------
+// HTTP Server handler
+// ------------
+
+// defaultSearchServer backs SearchServerHandler. Keeping one instance alive
+// across requests is what lets its datasetCache actually avoid re-decoding
+// dataset.xml on every call.
+var defaultSearchServer = NewSearchServer(NewXMLFileSource("./dataset.xml"))
+
+func SearchServerHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	token := r.Header.Get("AccessToken")
+	if token == "" || token != testToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	searchRequest, err := getValidInput(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, badRequestErrorResponse(err))
+		return
+	}
+
+	users, err := defaultSearchServer.getUsers(r.Context(), searchRequest)
+	if err != nil {
+		if r.Context().Err() != nil {
+			// the client is gone; nothing left to respond to
+			return
+		}
+
+		writeErrorResponse(w, http.StatusInternalServerError, ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Code:       CodeInternal,
+			Message:    err.Error(),
+			Error:      err.Error(),
+		})
+		return
+	}
+
+	responseJson, err := json.Marshal(usersResponse{Users: users})
+
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Code:       CodeInternal,
+			Message:    "Invalid data for json encoding",
+			Error:      "Invalid data for json encoding",
+		})
+		return
+	}
+
+	io.WriteString(w, string(responseJson))
+}
+
+// badRequestErrorResponse builds the ErrorResponse for a getValidInput
+// failure: a bad order_field gets its own code, any other bad param is
+// reported by name in Field.
+func badRequestErrorResponse(err error) ErrorResponse {
+	if err.Error() == "ErrorBadOrderField" {
+		return ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Code:       CodeBadOrderField,
+			Message:    "order_field is required",
+			Error:      "ErrorBadOrderField",
+		}
+	}
+
+	return ErrorResponse{
+		StatusCode: http.StatusBadRequest,
+		Code:       CodeBadParam,
+		Field:      err.Error(),
+		Message:    err.Error() + " is invalid",
+		OrderField: err.Error(),
+	}
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, body ErrorResponse) {
+	w.WriteHeader(status)
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+
+	io.WriteString(w, string(encoded))
+}
+
+func getValidInput(r *http.Request) (SearchRequest, error) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	if err != nil {
+		return SearchRequest{}, errors.New("limit")
+	}
+
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	if err != nil {
+		return SearchRequest{}, errors.New("offset")
+	}
+
+	orderBy, err := strconv.Atoi(r.URL.Query().Get("order_by"))
+
+	if err != nil {
+		return SearchRequest{}, errors.New("order_by")
+
+	}
+
+	orderField := r.URL.Query().Get("order_field")
+	if orderField == "" {
+		return SearchRequest{}, errors.New("ErrorBadOrderField")
+	}
+
+	query := r.URL.Query().Get("query")
+
+	return SearchRequest{
+		limit, offset, query, orderField, orderBy,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func synthesizeUsers(n int) []MyUser {
+	users := make([]MyUser, n)
+	for i := range users {
+		users[i] = MyUser{
+			Id:         n - i, // descending ids so sorting actually has work to do
+			FirstName:  fmt.Sprintf("First%d", i),
+			SecondName: fmt.Sprintf("Last%d", i),
+			Age:        i % 100,
+			About:      "benchmark user",
+			Gender:     "male",
+		}
+	}
+	return users
+}
+
+func BenchmarkSortUsersSingleThreaded(b *testing.B) {
+	users := synthesizeUsers(100000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		cp := make([]MyUser, len(users))
+		copy(cp, users)
+		b.StartTimer()
+
+		if err := sortUsers(ctx, cp, "Id", 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSortUsersShardMerge(b *testing.B) {
+	users := synthesizeUsers(100000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := shardSortMerge(ctx, users, "Id", 1, len(users)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSortUsersShardMergeFirstPage(b *testing.B) {
+	users := synthesizeUsers(100000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := shardSortMerge(ctx, users, "Id", 1, 25); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
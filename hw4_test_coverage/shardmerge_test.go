@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestShardSortMergeMatchesFullSort(t *testing.T) {
+	users := synthesizeUsers(500)
+	ctx := context.Background()
+
+	want := make([]MyUser, len(users))
+	copy(want, users)
+	if err := sortUsers(ctx, want, "Age", 1); err != nil {
+		t.Fatalf("sortUsers: %v", err)
+	}
+
+	got, err := shardSortMerge(ctx, users, "Age", 1, len(users))
+	if err != nil {
+		t.Fatalf("shardSortMerge: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d users, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Id != want[i].Id {
+			t.Fatalf("mismatch at %d: got id %d, want id %d", i, got[i].Id, want[i].Id)
+		}
+	}
+}
+
+func TestShardSortMergeStopsEarly(t *testing.T) {
+	users := synthesizeUsers(1000)
+
+	top, err := shardSortMerge(context.Background(), users, "Id", 1, 10)
+	if err != nil {
+		t.Fatalf("shardSortMerge: %v", err)
+	}
+	if len(top) != 10 {
+		t.Fatalf("got %d users, want 10", len(top))
+	}
+	for i := 1; i < len(top); i++ {
+		if top[i].Id < top[i-1].Id {
+			t.Fatalf("result not sorted ascending at %d", i)
+		}
+	}
+}